@@ -0,0 +1,618 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/google/uuid"
+	"github.com/sudomateo/yeetcode/internal/htmlmd"
+	"github.com/sudomateo/yeetcode/internal/leetcode"
+	"github.com/sudomateo/yeetcode/internal/scheduler"
+	"github.com/sudomateo/yeetcode/internal/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// duelTimeout is how long a duel stays open for either participant to
+// record a win via /solved.
+const duelTimeout = 24 * time.Hour
+
+// CommandHandler handles a single slash command's ApplicationCommand
+// interaction.
+type CommandHandler func(ctx context.Context, interaction *discordgo.Interaction, data *discordgo.ApplicationCommandInteractionData) (*discordgo.InteractionResponseData, error)
+
+// Handlers holds the dependencies shared by every command handler and
+// routes interactions to the method that implements them.
+type Handlers struct {
+	leetcode   *leetcode.Client
+	store      *store.Store
+	scheduler  *scheduler.Scheduler
+	discord    *discordgo.Session
+	logger     *slog.Logger
+	httpClient *http.Client
+}
+
+// NewHandlers builds a Handlers ready to route interactions. store may be
+// nil, in which case commands that require progress tracking report an
+// error instead of panicking.
+func NewHandlers(leetcodeClient *leetcode.Client, st *store.Store, sched *scheduler.Scheduler, discord *discordgo.Session, logger *slog.Logger) *Handlers {
+	return &Handlers{
+		leetcode:   leetcodeClient,
+		store:      st,
+		scheduler:  sched,
+		discord:    discord,
+		logger:     logger,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Router returns the command-name-to-handler map the HTTP layer dispatches
+// ApplicationCommand interactions against.
+func (h *Handlers) Router() map[string]CommandHandler {
+	return map[string]CommandHandler{
+		"leetcode":    h.LeetCode,
+		"schedule":    h.Schedule,
+		"solved":      h.Solved,
+		"stats":       h.Stats,
+		"leaderboard": h.Leaderboard,
+		"duel":        h.Duel,
+	}
+}
+
+// interactionUserID returns the ID of the user who triggered interaction,
+// whether it came from a guild (Member) or a DM (User).
+func interactionUserID(interaction *discordgo.Interaction) string {
+	if interaction.Member != nil && interaction.Member.User != nil {
+		return interaction.Member.User.ID
+	}
+	if interaction.User != nil {
+		return interaction.User.ID
+	}
+	return ""
+}
+
+// LeetCode handles the /leetcode command: it fetches a random question
+// matching the requested difficulty/tags/list/category and responds with a
+// bare link or a rendered embed depending on the format option.
+func (h *Handlers) LeetCode(ctx context.Context, interaction *discordgo.Interaction, data *discordgo.ApplicationCommandInteractionData) (*discordgo.InteractionResponseData, error) {
+	ctx, span := tracer.Start(ctx, "Handlers.LeetCode")
+	defer span.End()
+
+	var difficultyOpt, categoryOpt, listOpt, tagsOpt, format string
+
+	for _, v := range data.Options {
+		switch v.Name {
+		case "difficulty":
+			difficultyOpt = strings.ToUpper(v.StringValue())
+		case "category":
+			categoryOpt = v.StringValue()
+		case "list":
+			listOpt = v.StringValue()
+		case "tags":
+			tagsOpt = v.StringValue()
+		case "format":
+			format = v.StringValue()
+		}
+	}
+
+	var difficulty leetcode.Difficulty
+
+	switch leetcode.Difficulty(difficultyOpt) {
+	case leetcode.DifficultyEasy:
+		difficulty = leetcode.DifficultyEasy
+	case leetcode.DifficultyMedium:
+		difficulty = leetcode.DifficultyMedium
+	case leetcode.DifficultyHard:
+		difficulty = leetcode.DifficultyHard
+	default:
+		difficulty = leetcode.RandomDifficulty()
+	}
+
+	var tags []string
+	if tagsOpt != "" {
+		for _, tag := range strings.Split(tagsOpt, ",") {
+			tags = append(tags, strings.TrimSpace(tag))
+		}
+	}
+
+	span.SetAttributes(
+		attribute.String("leetcode.difficulty", string(difficulty)),
+		attribute.StringSlice("leetcode.tags", tags),
+		attribute.String("leetcode.category", categoryOpt),
+		attribute.String("leetcode.list", listOpt),
+	)
+
+	lcResp, err := h.leetcode.RandomQuestion(ctx, leetcode.RandomQuestionOptions{
+		Difficulty:   difficulty,
+		Tags:         tags,
+		CategorySlug: categoryOpt,
+		FavoriteSlug: listOpt,
+	})
+	if err != nil {
+		var unknownTagErr *leetcode.UnknownTagError
+		if errors.As(err, &unknownTagErr) {
+			return &discordgo.InteractionResponseData{
+				Flags:   discordgo.MessageFlagsEphemeral,
+				Content: unknownTagErr.Error(),
+			}, nil
+		}
+		var unknownListErr *leetcode.UnknownListError
+		if errors.As(err, &unknownListErr) {
+			return &discordgo.InteractionResponseData{
+				Flags:   discordgo.MessageFlagsEphemeral,
+				Content: unknownListErr.Error(),
+			}, nil
+		}
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed fetching leetcode question")
+		return nil, fmt.Errorf("failed fetching leetcode question: %w", err)
+	}
+
+	titleSlug := lcResp.Data.RandomQuestion.TitleSlug
+	span.SetAttributes(attribute.String("leetcode.title_slug", titleSlug))
+
+	if h.store != nil {
+		if err := h.store.RecordServed(ctx, interactionUserID(interaction)); err != nil {
+			h.logger.Warn("failed recording served problem", "error", err)
+		}
+	}
+
+	if format == "link" {
+		return &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("https://leetcode.com/problems/%s", titleSlug),
+		}, nil
+	}
+
+	question, err := h.leetcode.Question(ctx, titleSlug)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed fetching leetcode question metadata")
+		return nil, fmt.Errorf("failed fetching leetcode question metadata: %w", err)
+	}
+
+	return buildQuestionResponseData(question, format == "full"), nil
+}
+
+// optionString returns the string value of the named option, or the empty
+// string if it wasn't provided.
+func optionString(opts []*discordgo.ApplicationCommandInteractionDataOption, name string) string {
+	for _, opt := range opts {
+		if opt.Name == name {
+			return opt.StringValue()
+		}
+	}
+	return ""
+}
+
+// buildQuestionResponseData renders q as a Discord embed, including a "Show
+// hints" button when hints are available. When full is true, the rendered
+// question content is included as the embed description.
+func buildQuestionResponseData(q leetcode.Question, full bool) *discordgo.InteractionResponseData {
+	embed := &discordgo.MessageEmbed{
+		Title: q.Title,
+		URL:   fmt.Sprintf("https://leetcode.com/problems/%s", q.TitleSlug),
+		Color: difficultyColor(q.Difficulty),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Difficulty", Value: string(q.Difficulty), Inline: true},
+			{Name: "Acceptance", Value: fmt.Sprintf("%.1f%%", q.AcceptanceRate), Inline: true},
+			{Name: "👍 / 👎", Value: fmt.Sprintf("%d / %d", q.Likes, q.Dislikes), Inline: true},
+		},
+	}
+
+	for _, tag := range q.TopicTags {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Tag",
+			Value:  tag.Name,
+			Inline: true,
+		})
+	}
+
+	if full {
+		embed.Description = truncate(htmlmd.Convert(q.Content), 4000)
+	}
+
+	respData := &discordgo.InteractionResponseData{
+		Embeds: []*discordgo.MessageEmbed{embed},
+	}
+
+	if len(q.Hints) > 0 {
+		respData.Components = []discordgo.MessageComponent{
+			discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{
+					discordgo.Button{
+						Label:    "Show hints",
+						Style:    discordgo.SecondaryButton,
+						CustomID: fmt.Sprintf("hints:%s", q.TitleSlug),
+					},
+				},
+			},
+		}
+	}
+
+	return respData
+}
+
+// difficultyColor maps a question's difficulty to LeetCode's own brand
+// color so the embed's accent bar matches the site.
+func difficultyColor(d leetcode.Difficulty) int {
+	switch d {
+	case leetcode.DifficultyEasy:
+		return 0x00b8a3
+	case leetcode.DifficultyMedium:
+		return 0xffc01e
+	case leetcode.DifficultyHard:
+		return 0xff375f
+	default:
+		return 0x5865f2
+	}
+}
+
+// truncate cuts s down to its first max bytes and appends an ellipsis if it
+// was longer than that. Discord rejects embed descriptions over 4096
+// characters.
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max] + "…"
+}
+
+// Component handles a MessageComponent interaction. The only component the
+// bot emits today is the "Show hints" button attached to question embeds.
+func (h *Handlers) Component(ctx context.Context, customID string) (*discordgo.InteractionResponseData, error) {
+	ctx, span := tracer.Start(ctx, "Handlers.Component")
+	defer span.End()
+
+	if !strings.HasPrefix(customID, "hints:") {
+		return nil, fmt.Errorf("unknown message component %q", customID)
+	}
+
+	titleSlug := strings.TrimPrefix(customID, "hints:")
+
+	question, err := h.leetcode.Question(ctx, titleSlug)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed fetching leetcode question hints")
+		return nil, fmt.Errorf("failed fetching hints: %w", err)
+	}
+
+	content := "no hints available for this problem"
+	if len(question.Hints) > 0 {
+		var sb strings.Builder
+		for i, hint := range question.Hints {
+			fmt.Fprintf(&sb, "%d. %s\n", i+1, htmlmd.Convert(hint))
+		}
+		content = sb.String()
+	}
+
+	return &discordgo.InteractionResponseData{
+		Flags:   discordgo.MessageFlagsEphemeral,
+		Content: content,
+	}, nil
+}
+
+// Schedule dispatches the "add", "list", and "remove" subcommands of
+// /schedule.
+func (h *Handlers) Schedule(ctx context.Context, interaction *discordgo.Interaction, data *discordgo.ApplicationCommandInteractionData) (*discordgo.InteractionResponseData, error) {
+	ctx, span := tracer.Start(ctx, "Handlers.Schedule")
+	defer span.End()
+
+	if len(data.Options) == 0 {
+		return nil, errors.New("missing schedule subcommand")
+	}
+
+	sub := data.Options[0]
+	span.SetAttributes(attribute.String("schedule.subcommand", sub.Name))
+
+	switch sub.Name {
+	case "add":
+		entry := scheduler.Entry{
+			GuildID: interaction.GuildID,
+		}
+
+		for _, opt := range sub.Options {
+			switch opt.Name {
+			case "channel":
+				entry.ChannelID = opt.ChannelValue(nil).ID
+			case "cron":
+				entry.CronExpr = opt.StringValue()
+			case "difficulty":
+				entry.Difficulty = leetcode.Difficulty(strings.ToUpper(opt.StringValue()))
+			case "tags":
+				entry.Tags = strings.Split(opt.StringValue(), ",")
+			}
+		}
+
+		added, err := h.scheduler.Add(ctx, entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed adding schedule: %w", err)
+		}
+
+		return &discordgo.InteractionResponseData{
+			Flags:   discordgo.MessageFlagsEphemeral,
+			Content: fmt.Sprintf("scheduled `%s` in <#%s> (id `%s`)", added.CronExpr, added.ChannelID, added.ID),
+		}, nil
+
+	case "list":
+		allEntries, err := h.scheduler.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed listing schedules: %w", err)
+		}
+
+		var entries []scheduler.Entry
+		for _, entry := range allEntries {
+			if entry.GuildID == interaction.GuildID {
+				entries = append(entries, entry)
+			}
+		}
+
+		if len(entries) == 0 {
+			return &discordgo.InteractionResponseData{
+				Flags:   discordgo.MessageFlagsEphemeral,
+				Content: "no schedules configured for this guild",
+			}, nil
+		}
+
+		var sb strings.Builder
+		for _, entry := range entries {
+			fmt.Fprintf(&sb, "`%s` — `%s` in <#%s>\n", entry.ID, entry.CronExpr, entry.ChannelID)
+		}
+
+		return &discordgo.InteractionResponseData{
+			Flags:   discordgo.MessageFlagsEphemeral,
+			Content: sb.String(),
+		}, nil
+
+	case "remove":
+		var id string
+		for _, opt := range sub.Options {
+			if opt.Name == "id" {
+				id = opt.StringValue()
+				break
+			}
+		}
+
+		if err := h.scheduler.Remove(ctx, id); err != nil {
+			return nil, fmt.Errorf("failed removing schedule: %w", err)
+		}
+
+		return &discordgo.InteractionResponseData{
+			Flags:   discordgo.MessageFlagsEphemeral,
+			Content: fmt.Sprintf("removed schedule `%s`", id),
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown schedule subcommand %q", sub.Name)
+	}
+}
+
+// Solved handles /solved: it records the problem as solved for the invoking
+// user and, if a pending duel exists for that problem, resolves it in the
+// caller's favor. When LEETCODE_VERIFY_SUBMISSIONS is enabled and a
+// submission URL is supplied, the win is only credited once the public
+// submission page confirms it was accepted.
+func (h *Handlers) Solved(ctx context.Context, interaction *discordgo.Interaction, data *discordgo.ApplicationCommandInteractionData) (*discordgo.InteractionResponseData, error) {
+	ctx, span := tracer.Start(ctx, "Handlers.Solved")
+	defer span.End()
+
+	if h.store == nil {
+		return nil, errors.New("progress tracking is not configured")
+	}
+
+	slug := optionString(data.Options, "slug")
+	if slug == "" {
+		return nil, errors.New("missing slug")
+	}
+	submissionURL := optionString(data.Options, "submission_url")
+
+	if store.SubmissionScrapingEnabled() && submissionURL != "" {
+		accepted, err := store.VerifySubmission(ctx, h.httpClient, submissionURL, slug)
+		if err != nil {
+			span.RecordError(err)
+			return nil, fmt.Errorf("failed verifying submission: %w", err)
+		}
+		if !accepted {
+			return &discordgo.InteractionResponseData{
+				Flags:   discordgo.MessageFlagsEphemeral,
+				Content: "that submission doesn't look like an accepted submission for this problem",
+			}, nil
+		}
+	}
+
+	userID := interactionUserID(interaction)
+
+	stats, solved, err := h.store.RecordSolved(ctx, userID, slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed recording solved problem: %w", err)
+	}
+	if !solved {
+		return &discordgo.InteractionResponseData{
+			Flags:   discordgo.MessageFlagsEphemeral,
+			Content: fmt.Sprintf("you've already got credit for `%s`", slug),
+		}, nil
+	}
+
+	content := fmt.Sprintf("nice, `%s` marked solved — streak %d, %d solved total", slug, stats.Streak, stats.Solved)
+
+	duel, err := h.store.PendingDuelFor(ctx, userID, slug)
+	if err != nil && !errors.Is(err, store.ErrDuelNotFound) {
+		h.logger.Warn("failed checking for pending duel", "error", err)
+	} else if err == nil {
+		opponentID := duel.ChallengerID
+		if duel.ChallengerID == userID {
+			opponentID = duel.OpponentID
+		}
+
+		opponentStats, err := h.store.Stats(ctx, opponentID)
+		if err != nil {
+			h.logger.Warn("failed fetching duel opponent stats", "error", err)
+		} else {
+			delta := store.EloDelta(stats.ELO, opponentStats.ELO)
+
+			resolved, err := h.store.ResolveDuelWin(ctx, duel.ID, userID, delta, opponentID, -delta)
+			if err != nil {
+				h.logger.Warn("failed resolving duel", "error", err)
+			} else if resolved {
+				content = fmt.Sprintf("%s\n⚔️ you won your duel against <@%s> (+%d elo)!", content, opponentID, delta)
+			}
+		}
+	}
+
+	return &discordgo.InteractionResponseData{Content: content}, nil
+}
+
+// Stats handles /stats: it reports the invoking user's progress, or another
+// user's if the "user" option is supplied.
+func (h *Handlers) Stats(ctx context.Context, interaction *discordgo.Interaction, data *discordgo.ApplicationCommandInteractionData) (*discordgo.InteractionResponseData, error) {
+	ctx, span := tracer.Start(ctx, "Handlers.Stats")
+	defer span.End()
+
+	if h.store == nil {
+		return nil, errors.New("progress tracking is not configured")
+	}
+
+	targetID := interactionUserID(interaction)
+	for _, opt := range data.Options {
+		if opt.Name == "user" {
+			targetID = opt.UserValue(h.discord).ID
+		}
+	}
+
+	stats, err := h.store.Stats(ctx, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed fetching stats: %w", err)
+	}
+
+	return &discordgo.InteractionResponseData{
+		Flags:   discordgo.MessageFlagsEphemeral,
+		Content: fmt.Sprintf("<@%s> — served %d, solved %d, streak %d, elo %d", stats.UserID, stats.Served, stats.Solved, stats.Streak, stats.ELO),
+	}, nil
+}
+
+// leaderboardSize is how many users /leaderboard reports.
+const leaderboardSize = 10
+
+// Leaderboard handles /leaderboard: it reports the top users ranked by ELO.
+func (h *Handlers) Leaderboard(ctx context.Context, interaction *discordgo.Interaction, data *discordgo.ApplicationCommandInteractionData) (*discordgo.InteractionResponseData, error) {
+	ctx, span := tracer.Start(ctx, "Handlers.Leaderboard")
+	defer span.End()
+
+	if h.store == nil {
+		return nil, errors.New("progress tracking is not configured")
+	}
+
+	entries, err := h.store.Leaderboard(ctx, leaderboardSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed fetching leaderboard: %w", err)
+	}
+
+	if len(entries) == 0 {
+		return &discordgo.InteractionResponseData{Content: "no leaderboard data yet"}, nil
+	}
+
+	var sb strings.Builder
+	for i, entry := range entries {
+		fmt.Fprintf(&sb, "%d. <@%s> — %d elo (%d solved)\n", i+1, entry.UserID, entry.ELO, entry.Solved)
+	}
+
+	return &discordgo.InteractionResponseData{Content: sb.String()}, nil
+}
+
+// Duel handles /duel: it picks a random question, DMs both participants the
+// link, and creates a pending duel that /solved resolves.
+func (h *Handlers) Duel(ctx context.Context, interaction *discordgo.Interaction, data *discordgo.ApplicationCommandInteractionData) (*discordgo.InteractionResponseData, error) {
+	ctx, span := tracer.Start(ctx, "Handlers.Duel")
+	defer span.End()
+
+	if h.store == nil {
+		return nil, errors.New("progress tracking is not configured")
+	}
+
+	challengerID := interactionUserID(interaction)
+
+	var opponentID, difficultyOpt string
+	for _, opt := range data.Options {
+		switch opt.Name {
+		case "user":
+			opponentID = opt.UserValue(h.discord).ID
+		case "difficulty":
+			difficultyOpt = strings.ToUpper(opt.StringValue())
+		}
+	}
+
+	if opponentID == "" {
+		return nil, errors.New("missing duel opponent")
+	}
+	if opponentID == challengerID {
+		return &discordgo.InteractionResponseData{
+			Flags:   discordgo.MessageFlagsEphemeral,
+			Content: "you can't duel yourself",
+		}, nil
+	}
+
+	difficulty := leetcode.Difficulty(difficultyOpt)
+	switch difficulty {
+	case leetcode.DifficultyEasy, leetcode.DifficultyMedium, leetcode.DifficultyHard:
+	default:
+		difficulty = leetcode.RandomDifficulty()
+	}
+
+	lcResp, err := h.leetcode.RandomQuestion(ctx, leetcode.RandomQuestionOptions{Difficulty: difficulty})
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed fetching duel question: %w", err)
+	}
+
+	titleSlug := lcResp.Data.RandomQuestion.TitleSlug
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, fmt.Errorf("failed generating duel id: %w", err)
+	}
+
+	now := time.Now()
+	duel := store.Duel{
+		ID:           id.String(),
+		ChallengerID: challengerID,
+		OpponentID:   opponentID,
+		TitleSlug:    titleSlug,
+		Difficulty:   string(difficulty),
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(duelTimeout),
+	}
+
+	if err := h.store.CreateDuel(ctx, duel); err != nil {
+		return nil, fmt.Errorf("failed creating duel: %w", err)
+	}
+
+	link := fmt.Sprintf("https://leetcode.com/problems/%s", titleSlug)
+
+	for _, participant := range [][2]string{{challengerID, opponentID}, {opponentID, challengerID}} {
+		if err := h.notifyDuelist(participant[0], participant[1], link); err != nil {
+			h.logger.Warn("failed notifying duelist", "user_id", participant[0], "error", err)
+		}
+	}
+
+	return &discordgo.InteractionResponseData{
+		Content: fmt.Sprintf("⚔️ <@%s> challenged <@%s> to a %s duel: %s — first to `/solved` wins!", challengerID, opponentID, strings.ToLower(string(difficulty)), link),
+	}, nil
+}
+
+// notifyDuelist DMs userID the duel link, mentioning opponentID as their
+// challenger/opponent.
+func (h *Handlers) notifyDuelist(userID, opponentID, link string) error {
+	channel, err := h.discord.UserChannelCreate(userID)
+	if err != nil {
+		return fmt.Errorf("failed opening dm channel: %w", err)
+	}
+
+	_, err = h.discord.ChannelMessageSend(channel.ID, fmt.Sprintf("you've been challenged to a duel against <@%s>: %s", opponentID, link))
+	return err
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Handler dispatches Discord interactions through the command router,
+// regardless of whether they arrived over the HTTP interaction webhook or
+// the gateway. It tracks in-flight interactions so callers can drain them
+// during a graceful shutdown.
+type Handler struct {
+	handlers       *Handlers
+	router         map[string]CommandHandler
+	discord        *discordgo.Session
+	publicKeyBytes []byte
+
+	wg sync.WaitGroup
+}
+
+// NewHandler builds a Handler ready to be wired into the HTTP webhook, the
+// gateway, or both.
+func NewHandler(handlers *Handlers, discord *discordgo.Session, publicKeyBytes []byte) *Handler {
+	return &Handler{
+		handlers:       handlers,
+		router:         handlers.Router(),
+		discord:        discord,
+		publicKeyBytes: publicKeyBytes,
+	}
+}
+
+// HandleInteraction handles a single Discord interaction and returns the
+// response to send back. A non-nil error means nothing should be sent back
+// to Discord: the interaction was rejected outright (unsupported type,
+// unknown command) or a message component failed. Failures within a
+// command handler are instead reported as an ephemeral response with a nil
+// error.
+func (h *Handler) HandleInteraction(ctx context.Context, interaction *discordgo.Interaction) (*discordgo.InteractionResponse, error) {
+	h.wg.Add(1)
+	defer h.wg.Done()
+
+	ctx, span := tracer.Start(ctx, "interaction")
+	defer span.End()
+
+	requestID, err := uuid.NewRandom()
+	if err != nil {
+		span.RecordError(err)
+		requestID = uuid.UUID([16]byte{})
+	}
+	span.SetAttributes(attribute.String("request.id", requestID.String()))
+
+	if interaction.Type == discordgo.InteractionPing {
+		return &discordgo.InteractionResponse{Type: discordgo.InteractionResponsePong}, nil
+	}
+
+	if interaction.Type == discordgo.InteractionMessageComponent {
+		messageComponentData := interaction.MessageComponentData()
+
+		respData, err := h.handlers.Component(ctx, messageComponentData.CustomID)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed handling message component")
+			return nil, err
+		}
+
+		return &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: respData,
+		}, nil
+	}
+
+	if interaction.Type != discordgo.InteractionApplicationCommand {
+		span.SetStatus(codes.Error, "unsupported interaction type")
+		return nil, fmt.Errorf("unsupported interaction type %d", interaction.Type)
+	}
+
+	applicationCommandData := interaction.ApplicationCommandData()
+	span.SetAttributes(attribute.String("command.name", applicationCommandData.Name))
+
+	commandHandler, ok := h.router[applicationCommandData.Name]
+	if !ok {
+		span.SetStatus(codes.Error, "unknown command")
+		return nil, fmt.Errorf("unknown command %q", applicationCommandData.Name)
+	}
+
+	respData, err := commandHandler(ctx, interaction, &applicationCommandData)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed handling command")
+		respData = &discordgo.InteractionResponseData{
+			Flags:   discordgo.MessageFlagsEphemeral,
+			Content: fmt.Sprintf("failed handling /%s: %s", applicationCommandData.Name, err),
+		}
+	}
+
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: respData,
+	}, nil
+}
+
+// ServeHTTP implements the HTTP interaction webhook: it verifies the
+// request's signature, decodes the interaction, and delegates to
+// HandleInteraction. Ping is answered directly in the HTTP response body;
+// every other interaction is answered via the Discord REST callback so the
+// webhook response itself can just be an empty 200.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "interaction.http")
+	defer span.End()
+
+	if !discordgo.VerifyInteraction(r, h.publicKeyBytes) {
+		span.SetStatus(codes.Error, "failed verifying interaction")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	defer r.Body.Close()
+	var interaction discordgo.Interaction
+	if err := json.NewDecoder(r.Body).Decode(&interaction); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid interaction payload")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.HandleInteraction(ctx, &interaction)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed handling interaction")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if interaction.Type == discordgo.InteractionPing {
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed sending ping response")
+		}
+		return
+	}
+
+	if err := h.discord.InteractionRespond(&interaction, resp); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed responding to interaction")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// GatewayHandler returns a callback suitable for discordgo.Session's
+// AddHandler that dispatches InteractionCreate events received over the
+// gateway through the same command handlers used by the HTTP webhook.
+func (h *Handler) GatewayHandler() func(s *discordgo.Session, ic *discordgo.InteractionCreate) {
+	return func(s *discordgo.Session, ic *discordgo.InteractionCreate) {
+		ctx, span := tracer.Start(context.Background(), "interaction.gateway")
+		defer span.End()
+
+		resp, err := h.HandleInteraction(ctx, ic.Interaction)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed handling interaction")
+			return
+		}
+
+		if err := s.InteractionRespond(ic.Interaction, resp); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed responding to interaction")
+		}
+	}
+}
+
+// Wait blocks until every interaction already handed to HandleInteraction
+// has finished, for use during graceful shutdown.
+func (h *Handler) Wait() {
+	h.wg.Wait()
+}
@@ -0,0 +1,42 @@
+// Package htmlmd converts the small subset of HTML used in LeetCode question
+// bodies into Discord-flavored Markdown. It isn't a general-purpose
+// converter; it only handles the tags LeetCode actually emits.
+package htmlmd
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	tagPre       = regexp.MustCompile(`(?is)<pre>\s*<code>(.*?)</code>\s*</pre>`)
+	tagCode      = regexp.MustCompile(`(?is)<code>(.*?)</code>`)
+	tagStrong    = regexp.MustCompile(`(?is)<(strong|b)>(.*?)</(strong|b)>`)
+	tagEm        = regexp.MustCompile(`(?is)<(em|i)>(.*?)</(em|i)>`)
+	tagBreak     = regexp.MustCompile(`(?is)<br\s*/?>`)
+	tagListItem  = regexp.MustCompile(`(?is)<li>(.*?)</li>`)
+	tagParagraph = regexp.MustCompile(`(?is)</p>`)
+	tagAny       = regexp.MustCompile(`(?is)<[^>]+>`)
+	blankLines   = regexp.MustCompile(`\n{3,}`)
+)
+
+// Convert converts a LeetCode question's HTML content field into Markdown
+// suitable for a Discord message or embed description.
+func Convert(input string) string {
+	out := input
+
+	out = tagPre.ReplaceAllString(out, "```\n$1\n```\n")
+	out = tagCode.ReplaceAllString(out, "`$1`")
+	out = tagStrong.ReplaceAllString(out, "**$2**")
+	out = tagEm.ReplaceAllString(out, "*$2*")
+	out = tagListItem.ReplaceAllString(out, "- $1\n")
+	out = tagBreak.ReplaceAllString(out, "\n")
+	out = tagParagraph.ReplaceAllString(out, "\n\n")
+	out = tagAny.ReplaceAllString(out, "")
+
+	out = html.UnescapeString(out)
+	out = blankLines.ReplaceAllString(out, "\n\n")
+
+	return strings.TrimSpace(out)
+}
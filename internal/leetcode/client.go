@@ -0,0 +1,109 @@
+package leetcode
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Environment variables used to configure the default rate limit applied to
+// outgoing requests. Both are optional; see defaultRateLimit and
+// defaultRateBurst for their defaults.
+const (
+	envRateLimit = "LEETCODE_RATE_LIMIT"
+	envRateBurst = "LEETCODE_RATE_BURST"
+)
+
+const (
+	defaultRateLimit  = 1.0 // requests per second
+	defaultRateBurst  = 3
+	defaultMaxRetries = 5
+)
+
+// Client is the LeetCode API client.
+type Client struct {
+	httpClient *http.Client
+	topicTags  *topicTagCache
+}
+
+// clientConfig holds the tunables NewClient assembles from defaults,
+// environment variables, and Options, in that order of precedence.
+type clientConfig struct {
+	httpClient *http.Client
+	rateLimit  float64
+	rateBurst  int
+	maxRetries int
+}
+
+// Option configures a Client built by NewClient.
+type Option func(*clientConfig)
+
+// WithHTTPClient overrides the underlying http.Client used to make requests.
+// Its Transport is wrapped with the rate limiting and retry behavior
+// described by the other Options regardless of what's set here.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *clientConfig) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRateLimit overrides the token-bucket rate limit applied to outgoing
+// requests.
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(c *clientConfig) {
+		c.rateLimit = requestsPerSecond
+		c.rateBurst = burst
+	}
+}
+
+// WithMaxRetries overrides the number of retries attempted on a retryable
+// response before giving up.
+func WithMaxRetries(maxRetries int) Option {
+	return func(c *clientConfig) {
+		c.maxRetries = maxRetries
+	}
+}
+
+// NewClient builds and returns a LeetCode API client ready for use. By
+// default it rate limits outgoing requests to one per second with a burst
+// of three, retrying 429/5xx responses with exponential backoff and jitter,
+// and coalesces in-flight duplicate POSTs other than randomQuestion queries,
+// which always go upstream individually. Defaults can be overridden via
+// the LEETCODE_RATE_LIMIT/LEETCODE_RATE_BURST environment variables or the
+// supplied Options.
+func NewClient(opts ...Option) Client {
+	cfg := clientConfig{
+		rateLimit:  defaultRateLimit,
+		rateBurst:  defaultRateBurst,
+		maxRetries: defaultMaxRetries,
+	}
+
+	if v := os.Getenv(envRateLimit); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			cfg.rateLimit = f
+		}
+	}
+	if v := os.Getenv(envRateBurst); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.rateBurst = n
+		}
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	httpClient := http.Client{Timeout: 15 * time.Second}
+	if cfg.httpClient != nil {
+		httpClient = *cfg.httpClient
+	}
+	httpClient.Transport = newResilientTransport(httpClient.Transport, cfg.rateLimit, cfg.rateBurst, cfg.maxRetries)
+
+	return Client{
+		httpClient: &httpClient,
+		topicTags: &topicTagCache{
+			tags: make(map[string]struct{}),
+		},
+	}
+}
@@ -2,25 +2,27 @@ package leetcode
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"math/rand/v2"
 	"net/http"
-	"time"
+	"sync"
 )
 
-// Client is the LeetCode API client.
-type Client struct {
-	httpClient *http.Client
-}
+// Known category slugs accepted by the randomQuestion GraphQL API.
+const (
+	CategoryAlgorithms  = "algorithms"
+	CategoryDatabase    = "database"
+	CategoryShell       = "shell"
+	CategoryConcurrency = "concurrency"
+)
 
-// NewClient builds and returns a LeetCode API client ready for use.
-func NewClient() Client {
-	return Client{
-		httpClient: &http.Client{
-			Timeout: 15 * time.Second,
-		},
-	}
+// Known question-list slugs that can be passed as a RandomQuestionOptions
+// FavoriteSlug.
+var KnownQuestionLists = map[string]struct{}{
+	"top-interview-150": {},
+	"neetcode-150":      {},
 }
 
 // Difficulty represents the difficulty of LeetCode questions.
@@ -49,8 +51,8 @@ func RandomDifficulty() Difficulty {
 
 // The GraphQL query to fetch a random LeetCode question.
 const RandomQuestionQuery = `
-query randomQuestion($categorySlug: String, $filters: QuestionListFilterInput) {
-    randomQuestion(categorySlug: $categorySlug, filters: $filters) {
+query randomQuestion($categorySlug: String, $favoriteSlug: String, $filters: QuestionListFilterInput) {
+    randomQuestion(categorySlug: $categorySlug, favoriteSlug: $favoriteSlug, filters: $filters) {
         titleSlug
     }
 }`
@@ -66,6 +68,7 @@ type RandomQuestionRequest struct {
 // randomQuestion GraphQL API.
 type RandomQuestionVariables struct {
 	CategorySlug string                `json:"categorySlug"`
+	FavoriteSlug string                `json:"favoriteSlug,omitempty"`
 	Filters      RandomQuestionFilters `json:"filters"`
 }
 
@@ -86,13 +89,67 @@ type RandomQuestionResponse struct {
 	} `json:"data"`
 }
 
-// RandomQuestion retrieves a random LeetCode problem.
-func (c Client) RandomQuestion(difficulty Difficulty) (RandomQuestionResponse, error) {
+// RandomQuestionOptions configures a RandomQuestion request.
+type RandomQuestionOptions struct {
+	// Difficulty restricts the question to a specific difficulty. Leave
+	// empty to allow any difficulty.
+	Difficulty Difficulty
+	// Tags restricts the question to one or more topic tag slugs (e.g.
+	// "array", "dynamic-programming"). Each tag must be known to the
+	// cache populated by LoadTopicTags.
+	Tags []string
+	// CategorySlug restricts the question to a category such as
+	// CategoryAlgorithms or CategoryDatabase. Leave empty for the default
+	// category.
+	CategorySlug string
+	// FavoriteSlug restricts the question to a LeetCode question list
+	// such as "top-interview-150". Leave empty to pull from all
+	// questions.
+	FavoriteSlug string
+}
+
+// UnknownTagError is returned by ValidateTags and RandomQuestion when a
+// requested topic tag isn't known to the client's cached tag list.
+type UnknownTagError struct {
+	Tag string
+}
+
+// Error implements the error interface.
+func (e *UnknownTagError) Error() string {
+	return fmt.Sprintf("unknown leetcode tag %q", e.Tag)
+}
+
+// UnknownListError is returned by RandomQuestion when a requested question
+// list isn't in KnownQuestionLists.
+type UnknownListError struct {
+	List string
+}
+
+// Error implements the error interface.
+func (e *UnknownListError) Error() string {
+	return fmt.Sprintf("unknown leetcode list %q", e.List)
+}
+
+// RandomQuestion retrieves a random LeetCode problem matching opts.
+func (c Client) RandomQuestion(ctx context.Context, opts RandomQuestionOptions) (RandomQuestionResponse, error) {
+	if err := c.ValidateTags(opts.Tags); err != nil {
+		return RandomQuestionResponse{}, err
+	}
+
+	if opts.FavoriteSlug != "" {
+		if _, ok := KnownQuestionLists[opts.FavoriteSlug]; !ok {
+			return RandomQuestionResponse{}, &UnknownListError{List: opts.FavoriteSlug}
+		}
+	}
+
 	requestBody := RandomQuestionRequest{
 		Query: RandomQuestionQuery,
 		Variables: RandomQuestionVariables{
+			CategorySlug: opts.CategorySlug,
+			FavoriteSlug: opts.FavoriteSlug,
 			Filters: RandomQuestionFilters{
-				Difficulty: difficulty,
+				Difficulty: opts.Difficulty,
+				Tags:       opts.Tags,
 			},
 		},
 	}
@@ -102,7 +159,7 @@ func (c Client) RandomQuestion(difficulty Difficulty) (RandomQuestionResponse, e
 		return RandomQuestionResponse{}, fmt.Errorf("failed encoding request body: %w", err)
 	}
 
-	req, err := http.NewRequest(http.MethodPost, "https://leetcode.com/graphql", &body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://leetcode.com/graphql", &body)
 	if err != nil {
 		return RandomQuestionResponse{}, fmt.Errorf("failed building http request: %w", err)
 	}
@@ -123,3 +180,120 @@ func (c Client) RandomQuestion(difficulty Difficulty) (RandomQuestionResponse, e
 
 	return lcResp, nil
 }
+
+// The GraphQL query to fetch every known topic tag slug.
+const topicTagsQuery = `
+query questionTopicTags {
+    questionTopicTags {
+        edges {
+            node {
+                slug
+            }
+        }
+    }
+}`
+
+type topicTagsResponse struct {
+	Data struct {
+		QuestionTopicTags struct {
+			Edges []struct {
+				Node struct {
+					Slug string `json:"slug"`
+				} `json:"node"`
+			} `json:"edges"`
+		} `json:"questionTopicTags"`
+	} `json:"data"`
+}
+
+// topicTagCache holds the set of topic tag slugs known to LeetCode. It's
+// stored behind a pointer on Client so that copies of Client share the same
+// cache.
+type topicTagCache struct {
+	mu   sync.RWMutex
+	tags map[string]struct{}
+}
+
+func (c *topicTagCache) load(slugs []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, slug := range slugs {
+		c.tags[slug] = struct{}{}
+	}
+}
+
+func (c *topicTagCache) has(slug string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, ok := c.tags[slug]
+	return ok
+}
+
+func (c *topicTagCache) empty() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.tags) == 0
+}
+
+// LoadTopicTags fetches the full list of topic tag slugs from LeetCode and
+// caches them for use by ValidateTags. It should be called once at startup.
+func (c Client) LoadTopicTags(ctx context.Context) error {
+	requestBody := struct {
+		Query string `json:"query"`
+	}{
+		Query: topicTagsQuery,
+	}
+
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(requestBody); err != nil {
+		return fmt.Errorf("failed encoding request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://leetcode.com/graphql", &body)
+	if err != nil {
+		return fmt.Errorf("failed building http request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Origin", "https://leetcode.com")
+	req.Header.Set("Referer", "https://leetcode.com")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed making http request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var tagsResp topicTagsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tagsResp); err != nil {
+		return fmt.Errorf("failed decoding http response: %w", err)
+	}
+
+	slugs := make([]string, 0, len(tagsResp.Data.QuestionTopicTags.Edges))
+	for _, edge := range tagsResp.Data.QuestionTopicTags.Edges {
+		slugs = append(slugs, edge.Node.Slug)
+	}
+
+	c.topicTags.load(slugs)
+
+	return nil
+}
+
+// ValidateTags returns an *UnknownTagError for the first tag that isn't
+// known to the client's cached topic tag list. If the cache hasn't been
+// populated via LoadTopicTags yet, ValidateTags allows every tag through
+// rather than rejecting valid requests.
+func (c Client) ValidateTags(tags []string) error {
+	if c.topicTags.empty() {
+		return nil
+	}
+
+	for _, tag := range tags {
+		if !c.topicTags.has(tag) {
+			return &UnknownTagError{Tag: tag}
+		}
+	}
+
+	return nil
+}
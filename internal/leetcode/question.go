@@ -0,0 +1,103 @@
+package leetcode
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// The GraphQL query to fetch a single question's full metadata.
+const questionQuery = `
+query question($titleSlug: String!) {
+    question(titleSlug: $titleSlug) {
+        title
+        titleSlug
+        difficulty
+        topicTags {
+            name
+            slug
+        }
+        acRate
+        likes
+        dislikes
+        content
+        hints
+    }
+}`
+
+// QuestionRequest is the request that's sent to the question GraphQL API.
+type QuestionRequest struct {
+	Query     string            `json:"query"`
+	Variables QuestionVariables `json:"variables"`
+}
+
+// QuestionVariables are variables that can be set on the request to the
+// question GraphQL API.
+type QuestionVariables struct {
+	TitleSlug string `json:"titleSlug"`
+}
+
+// TopicTag is a single topic tag attached to a question.
+type TopicTag struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// Question is the full metadata for a single LeetCode problem.
+type Question struct {
+	Title          string     `json:"title"`
+	TitleSlug      string     `json:"titleSlug"`
+	Difficulty     Difficulty `json:"difficulty"`
+	TopicTags      []TopicTag `json:"topicTags"`
+	AcceptanceRate float64    `json:"acRate"`
+	Likes          int        `json:"likes"`
+	Dislikes       int        `json:"dislikes"`
+	Content        string     `json:"content"`
+	Hints          []string   `json:"hints"`
+}
+
+// questionResponse is the response sent back from the question GraphQL API.
+type questionResponse struct {
+	Data struct {
+		Question Question `json:"question"`
+	} `json:"data"`
+}
+
+// Question retrieves the full metadata for the problem identified by
+// titleSlug.
+func (c Client) Question(ctx context.Context, titleSlug string) (Question, error) {
+	requestBody := QuestionRequest{
+		Query: questionQuery,
+		Variables: QuestionVariables{
+			TitleSlug: titleSlug,
+		},
+	}
+
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(requestBody); err != nil {
+		return Question{}, fmt.Errorf("failed encoding request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://leetcode.com/graphql", &body)
+	if err != nil {
+		return Question{}, fmt.Errorf("failed building http request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Origin", "https://leetcode.com")
+	req.Header.Set("Referer", "https://leetcode.com")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return Question{}, fmt.Errorf("failed making http request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var qResp questionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&qResp); err != nil {
+		return Question{}, fmt.Errorf("failed decoding http response: %w", err)
+	}
+
+	return qResp.Data.Question, nil
+}
@@ -0,0 +1,243 @@
+package leetcode
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
+	"golang.org/x/time/rate"
+)
+
+var transportTracer = otel.GetTracerProvider().Tracer(
+	"github.com/sudomateo/yeetcode/internal/leetcode",
+	trace.WithSchemaURL(semconv.SchemaURL),
+)
+
+const (
+	backoffBase = 250 * time.Millisecond
+	backoffMax  = 10 * time.Second
+)
+
+// resilientTransport wraps an http.RoundTripper with a token-bucket rate
+// limiter, exponential-backoff retries on 429/5xx responses, and
+// singleflight coalescing of in-flight duplicate POSTs.
+type resilientTransport struct {
+	next       http.RoundTripper
+	limiter    *rate.Limiter
+	maxRetries int
+	group      singleflight.Group
+}
+
+// newResilientTransport wraps next, falling back to http.DefaultTransport
+// when next is nil.
+func newResilientTransport(next http.RoundTripper, requestsPerSecond float64, burst, maxRetries int) *resilientTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &resilientTransport{
+		next:       next,
+		limiter:    rate.NewLimiter(rate.Limit(requestsPerSecond), burst),
+		maxRetries: maxRetries,
+	}
+}
+
+// singleflightResponse is the cached shape of an *http.Response so it can be
+// safely replayed to every caller sharing a singleflight.Group.Do call.
+type singleflightResponse struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *resilientTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := drainBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only deterministic GraphQL POSTs are safe to coalesce: the request
+	// body must fully determine the response. randomQuestion queries are
+	// the opposite by design — each call is expected to return an
+	// independent pick — so they're excluded even though their bodies can
+	// collide byte-for-byte across callers.
+	if req.Method != http.MethodPost || body == nil || isRandomQuestionBody(body) {
+		return t.roundTripWithRetry(req, body)
+	}
+
+	key := req.URL.String() + ":" + string(body)
+
+	v, err, _ := t.group.Do(key, func() (any, error) {
+		resp, err := t.roundTripWithRetry(req, body)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading response body: %w", err)
+		}
+
+		return &singleflightResponse{
+			statusCode: resp.StatusCode,
+			header:     resp.Header.Clone(),
+			body:       respBody,
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sfResp := v.(*singleflightResponse)
+	return &http.Response{
+		Status:     strconv.Itoa(sfResp.statusCode) + " " + http.StatusText(sfResp.statusCode),
+		StatusCode: sfResp.statusCode,
+		Header:     sfResp.header,
+		Body:       io.NopCloser(bytes.NewReader(sfResp.body)),
+		Request:    req,
+	}, nil
+}
+
+// randomQuestionMarker identifies a request body as a randomQuestion GraphQL
+// query, which isRandomQuestionBody uses to exclude it from singleflight
+// coalescing.
+var randomQuestionMarker = []byte("randomQuestion(")
+
+// isRandomQuestionBody reports whether body is a randomQuestion GraphQL
+// request, which must never be coalesced since each call is expected to
+// return an independent random pick even when two bodies are identical.
+func isRandomQuestionBody(body []byte) bool {
+	return bytes.Contains(body, randomQuestionMarker)
+}
+
+// drainBody reads and restores req.Body, returning its bytes, or nil if the
+// request has no body.
+func drainBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
+
+// roundTripWithRetry performs req, retrying on 429/5xx responses with
+// exponential backoff and jitter, honoring a Retry-After header when
+// present. Each attempt is reported as its own child span.
+func (t *resilientTransport) roundTripWithRetry(req *http.Request, body []byte) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		attemptCtx, span := transportTracer.Start(ctx, "leetcode.transport.attempt")
+		span.SetAttributes(attribute.Int("http.retry_count", attempt))
+
+		if err := t.limiter.Wait(attemptCtx); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "rate limiter wait failed")
+			span.End()
+			return nil, fmt.Errorf("failed waiting for rate limiter: %w", err)
+		}
+
+		attemptReq := req.Clone(attemptCtx)
+		if body != nil {
+			attemptReq.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		resp, err := t.next.RoundTrip(attemptReq)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "transport error")
+			span.End()
+
+			if attempt >= t.maxRetries {
+				return nil, fmt.Errorf("leetcode request failed after %d attempts: %w", attempt+1, err)
+			}
+			if !sleep(ctx, backoff(attempt)) {
+				return nil, ctx.Err()
+			}
+			continue
+		}
+
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+
+		if !isRetryableStatus(resp.StatusCode) || attempt >= t.maxRetries {
+			span.End()
+			return resp, nil
+		}
+
+		wait := retryAfter(resp.Header, attempt)
+		span.SetAttributes(attribute.Float64("http.retry_after_seconds", wait.Seconds()))
+		span.End()
+
+		resp.Body.Close()
+
+		if !sleep(ctx, wait) {
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// sleep waits for d or until ctx is done, reporting which happened first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// isRetryableStatus reports whether statusCode warrants a retry.
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+// retryAfter returns how long to wait before the next attempt, honoring the
+// response's Retry-After header when present and falling back to an
+// exponential backoff with jitter otherwise.
+func retryAfter(header http.Header, attempt int) time.Duration {
+	if v := header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil {
+			return time.Duration(secs) * time.Second
+		}
+		if at, err := http.ParseTime(v); err == nil {
+			return time.Until(at)
+		}
+	}
+
+	return backoff(attempt)
+}
+
+// backoff computes an exponential backoff duration with jitter for the given
+// attempt number, capped at backoffMax.
+func backoff(attempt int) time.Duration {
+	d := backoffBase * time.Duration(1<<attempt)
+	if d > backoffMax {
+		d = backoffMax
+	}
+
+	jitter := time.Duration(rand.Int64N(int64(d/2) + 1))
+
+	return d/2 + jitter
+}
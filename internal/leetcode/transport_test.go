@@ -0,0 +1,111 @@
+package leetcode
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResilientTransportRetriesWithBackoff(t *testing.T) {
+	var attempts int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	transport := newResilientTransport(http.DefaultTransport, 1000, 1000, 5)
+	client := &http.Client{Transport: transport}
+
+	start := time.Now()
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL, bytes.NewBufferString(`{"query":"x"}`))
+	if err != nil {
+		t.Fatalf("failed building request: %v", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("failed making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	elapsed := time.Since(start)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Fatalf("got %d attempts, want 3", got)
+	}
+
+	// Two retries happened, each waiting at least half of the base backoff
+	// before jitter is added.
+	if elapsed < backoffBase/2 {
+		t.Fatalf("got elapsed %s, want at least %s (backoff should have delayed retries)", elapsed, backoffBase/2)
+	}
+}
+
+func TestResilientTransportSingleflightCoalescesDuplicateRequests(t *testing.T) {
+	var upstreamRequests int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&upstreamRequests, 1)
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer srv.Close()
+
+	transport := newResilientTransport(http.DefaultTransport, 1000, 1000, 1)
+	client := &http.Client{Transport: transport}
+
+	const concurrency = 5
+
+	var wg sync.WaitGroup
+	errs := make(chan error, concurrency)
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			req, err := http.NewRequest(http.MethodPost, srv.URL, bytes.NewBufferString(`{"query":"same"}`))
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				errs <- fmt.Errorf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	if got := atomic.LoadInt32(&upstreamRequests); got != 1 {
+		t.Fatalf("got %d upstream requests, want 1 (duplicate in-flight requests should be coalesced)", got)
+	}
+}
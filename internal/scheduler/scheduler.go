@@ -0,0 +1,205 @@
+// Package scheduler runs cron-triggered LeetCode question deliveries to
+// configured Discord channels.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"github.com/sudomateo/yeetcode/internal/leetcode"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.GetTracerProvider().Tracer(
+	"github.com/sudomateo/yeetcode/internal/scheduler",
+	trace.WithSchemaURL(semconv.SchemaURL),
+)
+
+// Entry describes a single recurring LeetCode post.
+type Entry struct {
+	ID         string              `json:"id"`
+	GuildID    string              `json:"guild_id"`
+	ChannelID  string              `json:"channel_id"`
+	CronExpr   string              `json:"cron_expr"`
+	Difficulty leetcode.Difficulty `json:"difficulty,omitempty"`
+	Tags       []string            `json:"tags,omitempty"`
+}
+
+// Store persists schedule entries. It's backed by a JSON file to start, but
+// the interface exists so a database-backed implementation can be swapped in
+// without touching the scheduler itself.
+type Store interface {
+	List(ctx context.Context) ([]Entry, error)
+	Add(ctx context.Context, entry Entry) error
+	Remove(ctx context.Context, id string) error
+}
+
+// Scheduler fans out LeetCode questions to Discord channels on a cron
+// schedule.
+type Scheduler struct {
+	cron     *cron.Cron
+	store    Store
+	discord  *discordgo.Session
+	leetcode *leetcode.Client
+	logger   *slog.Logger
+
+	mu      sync.Mutex
+	running bool
+	jobIDs  map[string]cron.EntryID
+}
+
+// New builds a Scheduler ready to be started with Start.
+func New(store Store, discord *discordgo.Session, leetcodeClient *leetcode.Client, logger *slog.Logger) *Scheduler {
+	return &Scheduler{
+		cron:     cron.New(),
+		store:    store,
+		discord:  discord,
+		leetcode: leetcodeClient,
+		logger:   logger,
+		jobIDs:   make(map[string]cron.EntryID),
+	}
+}
+
+// Start loads persisted entries from the store, schedules each of them, and
+// starts the cron goroutine. Start returns once every persisted entry has
+// been scheduled; the cron ticks continue to fire in the background until
+// ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) error {
+	entries, err := s.store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed listing schedule entries: %w", err)
+	}
+
+	s.mu.Lock()
+	for _, entry := range entries {
+		if err := s.scheduleLocked(entry); err != nil {
+			s.logger.Error("failed scheduling entry", "schedule.id", entry.ID, "error", err)
+		}
+	}
+	s.running = true
+	s.mu.Unlock()
+
+	s.cron.Start()
+
+	go func() {
+		<-ctx.Done()
+		s.Stop()
+	}()
+
+	return nil
+}
+
+// Stop stops the cron scheduler and waits for any in-flight tick to finish.
+func (s *Scheduler) Stop() {
+	<-s.cron.Stop().Done()
+}
+
+// Add persists a new schedule entry and, if the scheduler is running,
+// schedules it immediately.
+func (s *Scheduler) Add(ctx context.Context, entry Entry) (Entry, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed generating schedule id: %w", err)
+	}
+	entry.ID = id.String()
+
+	if _, err := cron.ParseStandard(entry.CronExpr); err != nil {
+		return Entry{}, fmt.Errorf("invalid cron expression: %w", err)
+	}
+
+	if err := s.store.Add(ctx, entry); err != nil {
+		return Entry{}, fmt.Errorf("failed persisting schedule entry: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running {
+		if err := s.scheduleLocked(entry); err != nil {
+			return Entry{}, err
+		}
+	}
+
+	return entry, nil
+}
+
+// Remove deletes a schedule entry and, if running, cancels its cron job.
+func (s *Scheduler) Remove(ctx context.Context, id string) error {
+	if err := s.store.Remove(ctx, id); err != nil {
+		return fmt.Errorf("failed removing schedule entry: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if jobID, ok := s.jobIDs[id]; ok {
+		s.cron.Remove(jobID)
+		delete(s.jobIDs, id)
+	}
+
+	return nil
+}
+
+// List returns every persisted schedule entry.
+func (s *Scheduler) List(ctx context.Context) ([]Entry, error) {
+	return s.store.List(ctx)
+}
+
+// scheduleLocked registers entry's cron job. Callers must hold s.mu.
+func (s *Scheduler) scheduleLocked(entry Entry) error {
+	jobID, err := s.cron.AddFunc(entry.CronExpr, func() {
+		s.tick(entry)
+	})
+	if err != nil {
+		return fmt.Errorf("failed adding cron job: %w", err)
+	}
+
+	s.jobIDs[entry.ID] = jobID
+	return nil
+}
+
+// tick fetches a random question for entry and posts it to entry's channel.
+func (s *Scheduler) tick(entry Entry) {
+	ctx, span := tracer.Start(context.Background(), "scheduler.tick")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("schedule.id", entry.ID),
+		attribute.String("schedule.guild_id", entry.GuildID),
+		attribute.String("schedule.channel_id", entry.ChannelID),
+	)
+
+	difficulty := entry.Difficulty
+	if difficulty == "" {
+		difficulty = leetcode.RandomDifficulty()
+	}
+
+	resp, err := s.leetcode.RandomQuestion(ctx, leetcode.RandomQuestionOptions{
+		Difficulty: difficulty,
+		Tags:       entry.Tags,
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed fetching leetcode question")
+		s.logger.Error("failed fetching leetcode question for schedule", "schedule.id", entry.ID, "error", err)
+		return
+	}
+
+	content := fmt.Sprintf("https://leetcode.com/problems/%s", resp.Data.RandomQuestion.TitleSlug)
+
+	if _, err := s.discord.ChannelMessageSend(entry.ChannelID, content, discordgo.WithContext(ctx)); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed posting scheduled question")
+		s.logger.Error("failed posting scheduled question", "schedule.id", entry.ID, "error", err)
+		return
+	}
+
+	span.SetAttributes(attribute.String("leetcode.title_slug", resp.Data.RandomQuestion.TitleSlug))
+}
@@ -0,0 +1,109 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// ErrNotFound is returned when a schedule entry doesn't exist.
+var ErrNotFound = errors.New("schedule entry not found")
+
+// JSONStore is a Store backed by a single JSON file on disk.
+type JSONStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewJSONStore builds a JSONStore that persists entries to path. The file is
+// created on the first Add if it doesn't already exist.
+func NewJSONStore(path string) *JSONStore {
+	return &JSONStore{path: path}
+}
+
+// List returns every entry persisted in the store.
+func (s *JSONStore) List(_ context.Context) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.readLocked()
+}
+
+// Add appends entry to the store.
+func (s *JSONStore) Add(_ context.Context, entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.readLocked()
+	if err != nil {
+		return err
+	}
+
+	entries = append(entries, entry)
+
+	return s.writeLocked(entries)
+}
+
+// Remove deletes the entry with the given id from the store.
+func (s *JSONStore) Remove(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.readLocked()
+	if err != nil {
+		return err
+	}
+
+	filtered := entries[:0]
+	found := false
+	for _, entry := range entries {
+		if entry.ID == id {
+			found = true
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+
+	if !found {
+		return ErrNotFound
+	}
+
+	return s.writeLocked(filtered)
+}
+
+func (s *JSONStore) readLocked() ([]Entry, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed reading schedule store: %w", err)
+	}
+
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed decoding schedule store: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (s *JSONStore) writeLocked(entries []Entry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed encoding schedule store: %w", err)
+	}
+
+	if err := os.WriteFile(s.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed writing schedule store: %w", err)
+	}
+
+	return nil
+}
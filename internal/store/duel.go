@@ -0,0 +1,123 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DuelStatus represents the lifecycle state of a Duel.
+type DuelStatus string
+
+// The enumeration of duel statuses.
+const (
+	DuelStatusPending DuelStatus = "pending"
+	DuelStatusWon     DuelStatus = "won"
+)
+
+// Duel is a head-to-head challenge between two users over a single problem.
+type Duel struct {
+	ID           string
+	ChallengerID string
+	OpponentID   string
+	TitleSlug    string
+	Difficulty   string
+	Status       DuelStatus
+	WinnerID     string
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+// ErrDuelNotFound is returned when no matching pending duel exists.
+var ErrDuelNotFound = errors.New("duel not found")
+
+// CreateDuel persists a new, pending duel.
+func (s *Store) CreateDuel(ctx context.Context, duel Duel) error {
+	const query = `
+INSERT INTO duels (id, challenger_id, opponent_id, title_slug, difficulty, status, winner_id, created_at, expires_at)
+VALUES (?, ?, ?, ?, ?, ?, '', ?, ?);
+`
+	_, err := s.db.ExecContext(ctx, query,
+		duel.ID, duel.ChallengerID, duel.OpponentID, duel.TitleSlug, duel.Difficulty,
+		string(DuelStatusPending), duel.CreatedAt.Unix(), duel.ExpiresAt.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed creating duel: %w", err)
+	}
+	return nil
+}
+
+// PendingDuelFor returns the most recent unexpired pending duel involving
+// userID for titleSlug, or ErrDuelNotFound if there isn't one.
+func (s *Store) PendingDuelFor(ctx context.Context, userID, titleSlug string) (Duel, error) {
+	const query = `
+SELECT id, challenger_id, opponent_id, title_slug, difficulty, status, winner_id, created_at, expires_at
+FROM duels
+WHERE title_slug = ? AND status = ? AND (challenger_id = ? OR opponent_id = ?) AND expires_at >= ?
+ORDER BY created_at DESC
+LIMIT 1;
+`
+	var (
+		duel                 Duel
+		status               string
+		createdAt, expiresAt int64
+	)
+
+	row := s.db.QueryRowContext(ctx, query, titleSlug, string(DuelStatusPending), userID, userID, time.Now().Unix())
+	err := row.Scan(&duel.ID, &duel.ChallengerID, &duel.OpponentID, &duel.TitleSlug, &duel.Difficulty, &status, &duel.WinnerID, &createdAt, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Duel{}, ErrDuelNotFound
+	}
+	if err != nil {
+		return Duel{}, fmt.Errorf("failed fetching pending duel: %w", err)
+	}
+
+	duel.Status = DuelStatus(status)
+	duel.CreatedAt = time.Unix(createdAt, 0)
+	duel.ExpiresAt = time.Unix(expiresAt, 0)
+
+	return duel, nil
+}
+
+// ResolveDuelWin marks duelID as won by winnerID and applies the winner/loser
+// ELO deltas, all within a single transaction. The completion update is
+// conditional on the duel still being pending, so if both participants race
+// to claim the same duel (e.g. by calling /solved within the same window),
+// only the first resolves it — resolved is false for every caller after
+// that, and no ELO is applied twice.
+func (s *Store) ResolveDuelWin(ctx context.Context, duelID, winnerID string, winnerDelta int, loserID string, loserDelta int) (resolved bool, err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	const completeQuery = `UPDATE duels SET status = ?, winner_id = ? WHERE id = ? AND status = ?;`
+	res, err := tx.ExecContext(ctx, completeQuery, string(DuelStatusWon), winnerID, duelID, string(DuelStatusPending))
+	if err != nil {
+		return false, fmt.Errorf("failed completing duel: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed checking duel completion: %w", err)
+	}
+	if rows == 0 {
+		return false, nil
+	}
+
+	if _, err := addELOTx(ctx, tx, winnerID, winnerDelta); err != nil {
+		return false, err
+	}
+	if _, err := addELOTx(ctx, tx, loserID, loserDelta); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed committing transaction: %w", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,14 @@
+package store
+
+import "math"
+
+// EloK is the K-factor used when adjusting ratings after a duel.
+const EloK = 32
+
+// EloDelta computes the rating change awarded to the winner of a duel
+// between winnerELO and loserELO using the standard logistic Elo formula.
+// The loser's rating should be adjusted by the negation of this value.
+func EloDelta(winnerELO, loserELO int) int {
+	expected := 1.0 / (1.0 + math.Pow(10, float64(loserELO-winnerELO)/400))
+	return int(math.Round(EloK * (1 - expected)))
+}
@@ -0,0 +1,265 @@
+// Package store persists per-user LeetCode progress — problems served,
+// problems solved, streaks, and ELO — along with duel state, in a
+// CGO-free SQLite database.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// defaultELO is the rating a user starts with before their first duel.
+const defaultELO = 1000
+
+// dateLayout formats the calendar day (UTC) a user last solved a problem
+// on, which RecordSolved uses to decide whether a streak continues.
+const dateLayout = "2006-01-02"
+
+// Store persists per-user LeetCode progress.
+type Store struct {
+	db *sql.DB
+}
+
+// New opens (creating if necessary) the SQLite database at path and
+// migrates its schema.
+func New(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening sqlite database: %w", err)
+	}
+
+	s := &Store{db: db}
+	if err := s.migrate(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Close closes the underlying database connection.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+const schema = `
+CREATE TABLE IF NOT EXISTS users (
+    user_id          TEXT PRIMARY KEY,
+    served           INTEGER NOT NULL DEFAULT 0,
+    solved           INTEGER NOT NULL DEFAULT 0,
+    streak           INTEGER NOT NULL DEFAULT 0,
+    last_solved_date TEXT NOT NULL DEFAULT '',
+    elo              INTEGER NOT NULL DEFAULT 1000
+);
+
+CREATE TABLE IF NOT EXISTS duels (
+    id            TEXT PRIMARY KEY,
+    challenger_id TEXT NOT NULL,
+    opponent_id   TEXT NOT NULL,
+    title_slug    TEXT NOT NULL,
+    difficulty    TEXT NOT NULL,
+    status        TEXT NOT NULL,
+    winner_id     TEXT NOT NULL DEFAULT '',
+    created_at    INTEGER NOT NULL,
+    expires_at    INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS solved_problems (
+    user_id    TEXT NOT NULL,
+    title_slug TEXT NOT NULL,
+    solved_at  INTEGER NOT NULL,
+    PRIMARY KEY (user_id, title_slug)
+);
+`
+
+func (s *Store) migrate(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, schema); err != nil {
+		return fmt.Errorf("failed migrating schema: %w", err)
+	}
+	return nil
+}
+
+// UserStats is a single user's recorded progress.
+type UserStats struct {
+	UserID         string
+	Served         int
+	Solved         int
+	Streak         int
+	LastSolvedDate string
+	ELO            int
+}
+
+// RecordServed increments the count of problems served to userID, creating
+// their row if it doesn't already exist.
+func (s *Store) RecordServed(ctx context.Context, userID string) error {
+	const query = `
+INSERT INTO users (user_id, served, elo) VALUES (?, 1, ?)
+ON CONFLICT(user_id) DO UPDATE SET served = served + 1;
+`
+	if _, err := s.db.ExecContext(ctx, query, userID, defaultELO); err != nil {
+		return fmt.Errorf("failed recording served problem: %w", err)
+	}
+	return nil
+}
+
+// RecordSolved credits userID with solving titleSlug, creating their row if
+// it doesn't already exist, and returns their updated stats along with
+// whether this call actually granted new credit. A user only gets credit for
+// a given problem once; calling RecordSolved again with the same titleSlug
+// returns their current stats with solved set to false. The streak advances
+// when the new solve falls on the calendar day (UTC) immediately following
+// last_solved_date, stays as it is when last_solved_date is already today,
+// and otherwise resets to 1.
+func (s *Store) RecordSolved(ctx context.Context, userID, titleSlug string) (stats UserStats, solved bool, err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return UserStats{}, false, fmt.Errorf("failed beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	const insertSolve = `
+INSERT INTO solved_problems (user_id, title_slug, solved_at) VALUES (?, ?, unixepoch())
+ON CONFLICT(user_id, title_slug) DO NOTHING;
+`
+	res, err := tx.ExecContext(ctx, insertSolve, userID, titleSlug)
+	if err != nil {
+		return UserStats{}, false, fmt.Errorf("failed recording solved problem: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return UserStats{}, false, fmt.Errorf("failed checking solved problem insert: %w", err)
+	}
+	if rows == 0 {
+		stats, err = statsTx(ctx, tx, userID)
+		if err != nil {
+			return UserStats{}, false, err
+		}
+		if err := tx.Commit(); err != nil {
+			return UserStats{}, false, fmt.Errorf("failed committing transaction: %w", err)
+		}
+		return stats, false, nil
+	}
+
+	current, err := statsTx(ctx, tx, userID)
+	if err != nil {
+		return UserStats{}, false, err
+	}
+
+	today := time.Now().UTC().Format(dateLayout)
+	streak := 1
+	if current.LastSolvedDate != "" {
+		yesterday := time.Now().UTC().AddDate(0, 0, -1).Format(dateLayout)
+		if current.LastSolvedDate == yesterday {
+			streak = current.Streak + 1
+		} else if current.LastSolvedDate == today {
+			streak = current.Streak
+		}
+	}
+
+	const upsertUser = `
+INSERT INTO users (user_id, solved, streak, last_solved_date, elo) VALUES (?, 1, ?, ?, ?)
+ON CONFLICT(user_id) DO UPDATE SET solved = solved + 1, streak = ?, last_solved_date = ?;
+`
+	if _, err := tx.ExecContext(ctx, upsertUser, userID, streak, today, defaultELO, streak, today); err != nil {
+		return UserStats{}, false, fmt.Errorf("failed updating solved stats: %w", err)
+	}
+
+	stats, err = statsTx(ctx, tx, userID)
+	if err != nil {
+		return UserStats{}, false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return UserStats{}, false, fmt.Errorf("failed committing transaction: %w", err)
+	}
+
+	return stats, true, nil
+}
+
+// AddELO adjusts userID's ELO by delta, creating their row if it doesn't
+// already exist.
+func (s *Store) AddELO(ctx context.Context, userID string, delta int) error {
+	_, err := addELOTx(ctx, s.db, userID, delta)
+	return err
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx, letting addELOTx run
+// either standalone or as part of a larger transaction such as
+// ResolveDuelWin.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// addELOTx adjusts userID's ELO by delta via e, creating their row if it
+// doesn't already exist.
+func addELOTx(ctx context.Context, e execer, userID string, delta int) (sql.Result, error) {
+	const query = `
+INSERT INTO users (user_id, elo) VALUES (?, ?)
+ON CONFLICT(user_id) DO UPDATE SET elo = elo + ?;
+`
+	res, err := e.ExecContext(ctx, query, userID, defaultELO+delta, delta)
+	if err != nil {
+		return nil, fmt.Errorf("failed adjusting elo: %w", err)
+	}
+	return res, nil
+}
+
+// Stats returns userID's recorded progress, or zero-value stats seeded with
+// the default ELO if they haven't interacted with the bot yet.
+func (s *Store) Stats(ctx context.Context, userID string) (UserStats, error) {
+	return statsTx(ctx, s.db, userID)
+}
+
+// queryRower is satisfied by both *sql.DB and *sql.Tx, letting statsTx run
+// either standalone or as part of RecordSolved's transaction.
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// statsTx returns userID's recorded progress via q, or zero-value stats
+// seeded with the default ELO if they haven't interacted with the bot yet.
+func statsTx(ctx context.Context, q queryRower, userID string) (UserStats, error) {
+	const query = `SELECT user_id, served, solved, streak, last_solved_date, elo FROM users WHERE user_id = ?;`
+
+	var stats UserStats
+	err := q.QueryRowContext(ctx, query, userID).
+		Scan(&stats.UserID, &stats.Served, &stats.Solved, &stats.Streak, &stats.LastSolvedDate, &stats.ELO)
+	if errors.Is(err, sql.ErrNoRows) {
+		return UserStats{UserID: userID, ELO: defaultELO}, nil
+	}
+	if err != nil {
+		return UserStats{}, fmt.Errorf("failed fetching stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// Leaderboard returns the top limit users ranked by ELO, descending.
+func (s *Store) Leaderboard(ctx context.Context, limit int) ([]UserStats, error) {
+	const query = `SELECT user_id, served, solved, streak, last_solved_date, elo FROM users ORDER BY elo DESC LIMIT ?;`
+
+	rows, err := s.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed fetching leaderboard: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []UserStats
+	for rows.Next() {
+		var row UserStats
+		if err := rows.Scan(&row.UserID, &row.Served, &row.Solved, &row.Streak, &row.LastSolvedDate, &row.ELO); err != nil {
+			return nil, fmt.Errorf("failed scanning leaderboard row: %w", err)
+		}
+		stats = append(stats, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed iterating leaderboard rows: %w", err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,106 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+)
+
+// allowedSubmissionHosts restricts VerifySubmission to LeetCode's own
+// domain so a submission URL can't be used to make the bot fetch
+// arbitrary internal or external addresses.
+var allowedSubmissionHosts = map[string]struct{}{
+	"leetcode.com":     {},
+	"www.leetcode.com": {},
+}
+
+// acceptedPattern matches the accepted-submission marker LeetCode embeds in
+// a public submission page. LeetCode doesn't offer a supported API for
+// this, so the check is a best-effort text match rather than a real parse
+// of the page's markup.
+var acceptedPattern = regexp.MustCompile(`"status_display"\s*:\s*"Accepted"`)
+
+// slugPattern matches the title slug LeetCode embeds for the submission's
+// problem, so a submission can be confirmed to belong to the problem it's
+// being credited against.
+var slugPattern = regexp.MustCompile(`"titleSlug"\s*:\s*"([a-z0-9-]+)"`)
+
+// SubmissionScrapingEnabled reports whether /solved should verify a
+// supplied submission URL against LeetCode's public submission page before
+// crediting a win. It's gated behind an environment variable because
+// scraping an unsupported page is inherently fragile.
+func SubmissionScrapingEnabled() bool {
+	return os.Getenv("LEETCODE_VERIFY_SUBMISSIONS") == "true"
+}
+
+// parseSubmissionURL validates that rawURL is an https URL on LeetCode's own
+// domain, so VerifySubmission can't be used to make the bot fetch arbitrary
+// internal or external addresses.
+func parseSubmissionURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing submission url: %w", err)
+	}
+
+	if u.Scheme != "https" {
+		return nil, fmt.Errorf("submission url must use https")
+	}
+
+	if _, ok := allowedSubmissionHosts[u.Hostname()]; !ok {
+		return nil, fmt.Errorf("submission url must be on leetcode.com")
+	}
+
+	return u, nil
+}
+
+// VerifySubmission fetches submissionURL and reports whether it shows an
+// accepted submission for titleSlug. submissionURL must be an https URL on
+// leetcode.com. httpClient's redirect policy is overridden so every
+// redirect hop is re-validated against allowedSubmissionHosts, too — an
+// initial URL on leetcode.com that 3xx-redirects elsewhere would otherwise
+// let the allowlist check above be bypassed entirely.
+func VerifySubmission(ctx context.Context, httpClient *http.Client, submissionURL, titleSlug string) (bool, error) {
+	u, err := parseSubmissionURL(submissionURL)
+	if err != nil {
+		return false, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return false, fmt.Errorf("failed building submission request: %w", err)
+	}
+
+	client := *httpClient
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if _, ok := allowedSubmissionHosts[req.URL.Hostname()]; !ok || req.URL.Scheme != "https" {
+			return fmt.Errorf("submission url redirected to disallowed host %q", req.URL.Hostname())
+		}
+		return nil
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed fetching submission page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return false, fmt.Errorf("failed reading submission page: %w", err)
+	}
+
+	if !acceptedPattern.Match(body) {
+		return false, nil
+	}
+
+	match := slugPattern.FindSubmatch(body)
+	if match == nil || string(match[1]) != titleSlug {
+		return false, nil
+	}
+
+	return true, nil
+}
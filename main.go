@@ -3,24 +3,21 @@ package main
 import (
 	"context"
 	"encoding/hex"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
-	"github.com/google/uuid"
 	"github.com/sudomateo/yeetcode/internal/leetcode"
+	"github.com/sudomateo/yeetcode/internal/scheduler"
+	"github.com/sudomateo/yeetcode/internal/store"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
 	"go.opentelemetry.io/otel/sdk/resource"
@@ -103,157 +100,97 @@ func run(ctx context.Context, logger *slog.Logger) error {
 
 	leetcodeClient := leetcode.NewClient()
 
-	mux := http.NewServeMux()
-
-	handleFunc := func(pattern string, handlerFunc func(http.ResponseWriter, *http.Request)) {
-		handler := otelhttp.WithRouteTag(pattern, http.HandlerFunc(handlerFunc))
-		mux.Handle(pattern, handler)
+	if err := leetcodeClient.LoadTopicTags(ctx); err != nil {
+		logger.Warn("failed loading leetcode topic tags, tag validation disabled", "error", err)
 	}
 
-	handleFunc("POST /", func(w http.ResponseWriter, r *http.Request) {
-		ctx, span := tracer.Start(r.Context(), "interaction")
-		defer span.End()
-
-		requestID, err := uuid.NewRandom()
-		if err != nil {
-			span.RecordError(err)
-			requestID = uuid.UUID([16]byte{})
-		}
-
-		span.SetAttributes(
-			attribute.String("request.id", requestID.String()),
-		)
+	ctx, cancel := signal.NotifyContext(ctx, syscall.SIGINT)
+	defer cancel()
 
-		if !discordgo.VerifyInteraction(r, publicKeyBytes) {
-			span.RecordError(err)
-			span.SetStatus(codes.Error, "failed verifying interaction")
-			w.WriteHeader(http.StatusBadRequest)
-			return
-		}
+	scheduleStorePath := os.Getenv("SCHEDULE_STORE_PATH")
+	if scheduleStorePath == "" {
+		scheduleStorePath = "schedules.json"
+	}
 
-		defer r.Body.Close()
-		var interaction discordgo.Interaction
-		if err := json.NewDecoder(r.Body).Decode(&interaction); err != nil {
-			span.RecordError(err)
-			span.SetStatus(codes.Error, "invalid interaction payload")
-			w.WriteHeader(http.StatusBadRequest)
-			return
-		}
+	sched := scheduler.New(scheduler.NewJSONStore(scheduleStorePath), discordClient, &leetcodeClient, logger)
+	if err := sched.Start(ctx); err != nil {
+		return fmt.Errorf("failed starting scheduler: %w", err)
+	}
 
-		if interaction.Type == discordgo.InteractionPing {
-			w.WriteHeader(http.StatusOK)
-			resp := discordgo.InteractionResponse{
-				Type: discordgo.InteractionResponsePong,
-			}
+	storePath := os.Getenv("STORE_PATH")
+	if storePath == "" {
+		storePath = "yeetcode.db"
+	}
 
-			if err := json.NewEncoder(w).Encode(resp); err != nil {
-				span.RecordError(err)
-				span.SetStatus(codes.Error, "failed sending ping response")
-				w.WriteHeader(http.StatusInternalServerError)
-				return
-			}
+	st, err := store.New(storePath)
+	if err != nil {
+		return fmt.Errorf("failed opening store: %w", err)
+	}
+	defer st.Close()
 
-			return
-		}
+	handlers := NewHandlers(&leetcodeClient, st, sched, discordClient, logger)
+	interactionHandler := NewHandler(handlers, discordClient, publicKeyBytes)
 
-		if interaction.Type != discordgo.InteractionApplicationCommand {
-			span.RecordError(err)
-			span.SetStatus(codes.Error, "unsupported interaction type")
-			w.WriteHeader(http.StatusBadRequest)
-			return
-		}
+	mode := os.Getenv("MODE")
+	if mode == "" {
+		mode = "http"
+	}
+	if mode != "http" && mode != "gateway" && mode != "both" {
+		return fmt.Errorf("invalid MODE %q: must be one of http, gateway, both", mode)
+	}
 
-		applicationCommandData := interaction.ApplicationCommandData()
-		lcResp, err := fetchLeetCodeQuestion(ctx, &leetcodeClient, &applicationCommandData)
-		if err != nil {
-			span.RecordError(err)
-			span.SetStatus(codes.Error, "failed to retreive leetcode question")
-			w.WriteHeader(http.StatusInternalServerError)
-			return
-		}
+	var srv *http.Server
+	errCh := make(chan error, 1)
 
-		interactionResp := discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: fmt.Sprintf("https://leetcode.com/problems/%s", lcResp.Data.RandomQuestion.TitleSlug),
-			},
-		}
+	if mode == "http" || mode == "both" {
+		mux := http.NewServeMux()
+		mux.Handle("POST /", otelhttp.WithRouteTag("POST /", interactionHandler))
 
-		if err := discordClient.InteractionRespond(&interaction, &interactionResp); err != nil {
-			span.RecordError(err)
-			span.SetStatus(codes.Error, "failed responding to interaction")
-			w.WriteHeader(http.StatusInternalServerError)
-			return
+		srv = &http.Server{
+			Addr:    ":3000",
+			Handler: mux,
 		}
 
-		span.SetAttributes(
-			attribute.String("leetcode.title_slug", lcResp.Data.RandomQuestion.TitleSlug),
-		)
-
-		w.WriteHeader(http.StatusOK)
-		return
-	})
+		logger.Info("starting http server", "addr", srv.Addr)
 
-	srv := http.Server{
-		Addr:    ":3000",
-		Handler: mux,
+		go func() {
+			errCh <- srv.ListenAndServe()
+		}()
 	}
 
-	logger.Info("starting http server", "addr", srv.Addr)
+	if mode == "gateway" || mode == "both" {
+		discordClient.AddHandler(interactionHandler.GatewayHandler())
 
-	ctx, cancel := signal.NotifyContext(ctx, syscall.SIGINT)
-	defer cancel()
-
-	errCh := make(chan error, 1)
+		if err := discordClient.Open(); err != nil {
+			return fmt.Errorf("failed opening discord gateway session: %w", err)
+		}
 
-	go func() {
-		errCh <- srv.ListenAndServe()
-	}()
+		logger.Info("connected to discord gateway")
+	}
 
 	select {
 	case <-ctx.Done():
 		logger.Info("shutting down gracefully", "reason", ctx.Err())
-		shutdownCtx, shutdownCtxCancel := context.WithTimeout(context.Background(), 15*time.Second)
-		defer shutdownCtxCancel()
-		if err := srv.Shutdown(shutdownCtx); err != nil {
-			logger.Error("shutting down forcefully", "error", err)
-			return srv.Close()
-		}
-	case err := <-errCh:
-		return err
-	}
 
-	return nil
-}
-
-// This is just here to have a parent/child span relationship for Axiom.
-func fetchLeetCodeQuestion(ctx context.Context, leetcodeClient *leetcode.Client, applicationCommandData *discordgo.ApplicationCommandInteractionData) (leetcode.RandomQuestionResponse, error) {
-	ctx, span := tracer.Start(ctx, "fetchLeetCodeQuestion")
-	defer span.End()
+		if mode == "gateway" || mode == "both" {
+			if err := discordClient.Close(); err != nil {
+				logger.Error("failed closing discord gateway session", "error", err)
+			}
+		}
 
-	var difficultyOpt string
+		interactionHandler.Wait()
 
-	for _, v := range applicationCommandData.Options {
-		if v.Name == "difficulty" {
-			difficultyOpt = strings.ToUpper(v.StringValue())
-			break
+		if srv != nil {
+			shutdownCtx, shutdownCtxCancel := context.WithTimeout(context.Background(), 15*time.Second)
+			defer shutdownCtxCancel()
+			if err := srv.Shutdown(shutdownCtx); err != nil {
+				logger.Error("shutting down forcefully", "error", err)
+				return srv.Close()
+			}
 		}
+	case err := <-errCh:
+		return err
 	}
 
-	var difficulty leetcode.Difficulty
-
-	switch leetcode.Difficulty(difficultyOpt) {
-	case leetcode.DifficultyEasy:
-		difficulty = leetcode.DifficultyEasy
-	case leetcode.DifficultyMedium:
-		difficulty = leetcode.DifficultyMedium
-	case leetcode.DifficultyHard:
-		difficulty = leetcode.DifficultyHard
-	default:
-		difficulty = leetcode.RandomDifficulty()
-	}
-
-	span.SetAttributes(attribute.String("leetcode.difficulty", string(difficulty)))
-
-	return leetcodeClient.RandomQuestion(difficulty)
+	return nil
 }